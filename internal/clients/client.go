@@ -0,0 +1,41 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
+)
+
+// AadClient holds the service principal clients threaded through every
+// resource's CRUD functions as `meta`. Which concrete implementation backs
+// ServicePrincipalsClient - AAD Graph or Microsoft Graph - is decided once,
+// in NewAadClient, based on the provider's `use_microsoft_graph` argument.
+type AadClient struct {
+	StopContext context.Context
+
+	ServicePrincipalsClient ServicePrincipalsClient
+
+	// FederatedIdentityCredentialsClient is only populated when the
+	// provider is configured against Microsoft Graph - AAD Graph has no
+	// equivalent API. Resources that need it must check for nil and fail
+	// with a clear error rather than assume it's always present.
+	FederatedIdentityCredentialsClient FederatedIdentityCredentialsClient
+}
+
+// NewAadClient selects the Service Principal backend based on
+// useMicrosoftGraph (populated from the provider's `use_microsoft_graph`
+// argument, which itself defaults from the ARM_USE_MICROSOFT_GRAPH
+// environment variable) and wires up the matching implementation.
+func NewAadClient(ctx context.Context, useMicrosoftGraph bool, aadGraphServicePrincipals *graphrbac.ServicePrincipalsClient, msGraphClient *msgraphsdk.GraphServiceClient) *AadClient {
+	client := &AadClient{StopContext: ctx}
+
+	if useMicrosoftGraph {
+		client.ServicePrincipalsClient = NewMsGraphServicePrincipalsClient(msGraphClient)
+		client.FederatedIdentityCredentialsClient = NewMsGraphFederatedIdentityCredentialsClient(msGraphClient)
+		return client
+	}
+
+	client.ServicePrincipalsClient = NewAadGraphServicePrincipalsClient(aadGraphServicePrincipals)
+	return client
+}
@@ -0,0 +1,27 @@
+package clients
+
+import "context"
+
+// FederatedIdentityCredential is the neutral representation of a service
+// principal's federatedIdentityCredentials entry - there is no AAD Graph
+// equivalent, so unlike ServicePrincipalsClient this isn't translated from
+// a shared wire type.
+type FederatedIdentityCredential struct {
+	Id          string
+	Name        string
+	Issuer      string
+	Subject     string
+	Audiences   []string
+	Description string
+}
+
+// FederatedIdentityCredentialsClient is only implemented against Microsoft
+// Graph: federated identity credentials were introduced after AAD Graph was
+// frozen, so there is no AAD Graph backend for it. Callers should check for
+// a nil client on clients.AadClient and fail with a clear error rather than
+// a nil pointer panic when the provider is configured with AAD Graph only.
+type FederatedIdentityCredentialsClient interface {
+	Get(ctx context.Context, objectId, credentialId string) (FederatedIdentityCredential, error)
+	Create(ctx context.Context, objectId string, credential FederatedIdentityCredential) (FederatedIdentityCredential, error)
+	Delete(ctx context.Context, objectId, credentialId string) error
+}
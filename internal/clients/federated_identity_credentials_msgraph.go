@@ -0,0 +1,74 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+
+	"github.com/terraform-providers/terraform-provider-azuread/internal/services/aadgraph/graph"
+)
+
+type msGraphFederatedIdentityCredentialsClient struct {
+	client *msgraphsdk.GraphServiceClient
+}
+
+func NewMsGraphFederatedIdentityCredentialsClient(client *msgraphsdk.GraphServiceClient) FederatedIdentityCredentialsClient {
+	return &msGraphFederatedIdentityCredentialsClient{client: client}
+}
+
+func (c *msGraphFederatedIdentityCredentialsClient) Get(ctx context.Context, objectId, credentialId string) (FederatedIdentityCredential, error) {
+	fic, err := c.client.ServicePrincipals().ByServicePrincipalId(objectId).FederatedIdentityCredentials().ByFederatedIdentityCredentialId(credentialId).Get(ctx, nil)
+	if err != nil {
+		if msGraphWasNotFound(err) {
+			return FederatedIdentityCredential{}, &graph.NotFoundError{Kind: "Federated Identity Credential", Id: credentialId}
+		}
+		return FederatedIdentityCredential{}, err
+	}
+	return federatedIdentityCredentialFromMsGraph(fic), nil
+}
+
+func (c *msGraphFederatedIdentityCredentialsClient) Create(ctx context.Context, objectId string, credential FederatedIdentityCredential) (FederatedIdentityCredential, error) {
+	requestBody := models.NewFederatedIdentityCredential()
+	requestBody.SetName(&credential.Name)
+	requestBody.SetIssuer(&credential.Issuer)
+	requestBody.SetSubject(&credential.Subject)
+	requestBody.SetAudiences(credential.Audiences)
+	if credential.Description != "" {
+		requestBody.SetDescription(&credential.Description)
+	}
+
+	fic, err := c.client.ServicePrincipals().ByServicePrincipalId(objectId).FederatedIdentityCredentials().Post(ctx, requestBody, nil)
+	if err != nil {
+		return FederatedIdentityCredential{}, fmt.Errorf("creating federated identity credential %q for service principal %q: %+v", credential.Name, objectId, err)
+	}
+
+	return federatedIdentityCredentialFromMsGraph(fic), nil
+}
+
+func (c *msGraphFederatedIdentityCredentialsClient) Delete(ctx context.Context, objectId, credentialId string) error {
+	return c.client.ServicePrincipals().ByServicePrincipalId(objectId).FederatedIdentityCredentials().ByFederatedIdentityCredentialId(credentialId).Delete(ctx, nil)
+}
+
+func federatedIdentityCredentialFromMsGraph(fic models.FederatedIdentityCredentialable) FederatedIdentityCredential {
+	result := FederatedIdentityCredential{
+		Audiences: fic.GetAudiences(),
+	}
+	if id := fic.GetId(); id != nil {
+		result.Id = *id
+	}
+	if name := fic.GetName(); name != nil {
+		result.Name = *name
+	}
+	if issuer := fic.GetIssuer(); issuer != nil {
+		result.Issuer = *issuer
+	}
+	if subject := fic.GetSubject(); subject != nil {
+		result.Subject = *subject
+	}
+	if description := fic.GetDescription(); description != nil {
+		result.Description = *description
+	}
+	return result
+}
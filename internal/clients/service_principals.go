@@ -0,0 +1,48 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// ServicePrincipalsClient is implemented by both the legacy Azure AD Graph
+// client and the Microsoft Graph client, so that the resources under
+// internal/services/aadgraph run against either backend with a single code
+// path and state shape while the provider migrates off AAD Graph ahead of
+// its deprecation.
+//
+// Wire types from the `graphrbac` package are used as the common currency
+// between the two implementations (rather than introducing a parallel
+// neutral model) to keep the first migration step low-risk; the
+// msGraphServicePrincipalsClient implementation in
+// service_principals_msgraph.go translates Microsoft
+// Graph's representation into these shapes on the way out.
+type ServicePrincipalsClient interface {
+	Get(ctx context.Context, objectId string) (graphrbac.ServicePrincipal, error)
+
+	ListKeyCredentials(ctx context.Context, objectId string) (graphrbac.KeyCredentialListResult, error)
+	UpdateKeyCredentials(ctx context.Context, objectId string, parameters graphrbac.KeyCredentialsUpdateParameters) (autorest.Response, error)
+
+	ListPasswordCredentials(ctx context.Context, objectId string) (graphrbac.PasswordCredentialListResult, error)
+	UpdatePasswordCredentials(ctx context.Context, objectId string, parameters graphrbac.PasswordCredentialsUpdateParameters) (autorest.Response, error)
+
+	// AddTokenSigningCertificate asks the backend to generate a fresh
+	// self-signed key credential for the given service principal, with
+	// Azure AD retaining the private key. There is no equivalent wire type
+	// shared between AAD Graph and Microsoft Graph for this, so the result
+	// is returned as the neutral TokenSigningCertificate.
+	AddTokenSigningCertificate(ctx context.Context, objectId string, displayName string, endDate *string) (TokenSigningCertificate, error)
+}
+
+// TokenSigningCertificate is the result of asking a backend to mint a new
+// self-signed certificate on behalf of a service principal, such as via
+// Microsoft Graph's `addTokenSigningCertificate` action.
+type TokenSigningCertificate struct {
+	KeyId       string
+	Thumbprint  string
+	StartDate   string
+	EndDate     string
+	Certificate string
+}
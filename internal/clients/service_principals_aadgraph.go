@@ -0,0 +1,62 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest"
+
+	"github.com/terraform-providers/terraform-provider-azuread/internal/services/aadgraph/graph"
+	"github.com/terraform-providers/terraform-provider-azuread/internal/utils"
+)
+
+// aadGraphServicePrincipalsClient adapts the existing graphrbac client onto
+// the ServicePrincipalsClient interface. It is a thin pass-through, since
+// graphrbac's wire types are also the interface's common currency.
+type aadGraphServicePrincipalsClient struct {
+	client *graphrbac.ServicePrincipalsClient
+}
+
+func NewAadGraphServicePrincipalsClient(client *graphrbac.ServicePrincipalsClient) ServicePrincipalsClient {
+	return &aadGraphServicePrincipalsClient{client: client}
+}
+
+func (c *aadGraphServicePrincipalsClient) Get(ctx context.Context, objectId string) (graphrbac.ServicePrincipal, error) {
+	sp, err := c.client.Get(ctx, objectId)
+	if err != nil {
+		if utils.ResponseWasNotFound(sp.Response) {
+			return sp, &graph.NotFoundError{Kind: "Service Principal", Id: objectId}
+		}
+		return sp, err
+	}
+	return sp, nil
+}
+
+func (c *aadGraphServicePrincipalsClient) ListKeyCredentials(ctx context.Context, objectId string) (graphrbac.KeyCredentialListResult, error) {
+	return c.client.ListKeyCredentials(ctx, objectId)
+}
+
+func (c *aadGraphServicePrincipalsClient) UpdateKeyCredentials(ctx context.Context, objectId string, parameters graphrbac.KeyCredentialsUpdateParameters) (autorest.Response, error) {
+	return c.client.UpdateKeyCredentials(ctx, objectId, parameters)
+}
+
+func (c *aadGraphServicePrincipalsClient) ListPasswordCredentials(ctx context.Context, objectId string) (graphrbac.PasswordCredentialListResult, error) {
+	return c.client.ListPasswordCredentials(ctx, objectId)
+}
+
+func (c *aadGraphServicePrincipalsClient) UpdatePasswordCredentials(ctx context.Context, objectId string, parameters graphrbac.PasswordCredentialsUpdateParameters) (autorest.Response, error) {
+	return c.client.UpdatePasswordCredentials(ctx, objectId, parameters)
+}
+
+// AddTokenSigningCertificate has no AAD Graph equivalent: Microsoft Graph's
+// `addTokenSigningCertificate` action has Azure AD generate the key pair
+// and retain the private key server-side, which AAD Graph's key credential
+// APIs cannot do - they only accept certificate material a caller already
+// has, with no way to hand Azure AD a private key to sign with. An earlier
+// version of this method faked the action by generating a self-signed
+// certificate locally and discarding the private key, which silently
+// produced a non-functional signing certificate. Fail clearly instead.
+func (c *aadGraphServicePrincipalsClient) AddTokenSigningCertificate(ctx context.Context, objectId string, displayName string, endDate *string) (TokenSigningCertificate, error) {
+	return TokenSigningCertificate{}, fmt.Errorf("azuread_service_principal_token_signing_certificate requires Microsoft Graph: set `use_microsoft_graph = true` on the provider, since AAD Graph has no equivalent to the `addTokenSigningCertificate` action")
+}
@@ -0,0 +1,223 @@
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest"
+	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/microsoftgraph/msgraph-sdk-go/models/odataerrors"
+	servicePrincipals "github.com/microsoftgraph/msgraph-sdk-go/serviceprincipals"
+
+	"github.com/terraform-providers/terraform-provider-azuread/internal/services/aadgraph/graph"
+)
+
+// msGraphServicePrincipalsClient adapts the Microsoft Graph SDK onto the
+// ServicePrincipalsClient interface, translating to and from the graphrbac
+// wire types so that resources written against the older AAD Graph client
+// continue to work unchanged regardless of which backend the provider is
+// configured to use.
+type msGraphServicePrincipalsClient struct {
+	client *msgraphsdk.GraphServiceClient
+}
+
+func NewMsGraphServicePrincipalsClient(client *msgraphsdk.GraphServiceClient) ServicePrincipalsClient {
+	return &msGraphServicePrincipalsClient{client: client}
+}
+
+func (c *msGraphServicePrincipalsClient) Get(ctx context.Context, objectId string) (graphrbac.ServicePrincipal, error) {
+	sp, err := c.client.ServicePrincipals().ByServicePrincipalId(objectId).Get(ctx, nil)
+	if err != nil {
+		if msGraphWasNotFound(err) {
+			return graphrbac.ServicePrincipal{}, &graph.NotFoundError{Kind: "Service Principal", Id: objectId}
+		}
+		return graphrbac.ServicePrincipal{}, err
+	}
+	return graphrbac.ServicePrincipal{ObjectID: sp.GetId()}, nil
+}
+
+// msGraphWasNotFound reports whether err is a Microsoft Graph ODataError
+// carrying a 404, the equivalent of utils.ResponseWasNotFound for the AAD
+// Graph backend. The methods of this backend use it to return a
+// *graph.NotFoundError, so callers outside this package - such as
+// servicePrincipalCredentialResource's ParentGet - can rely on that error
+// rather than on an autorest.Response, which this backend never populates.
+func msGraphWasNotFound(err error) bool {
+	var oDataError *odataerrors.ODataError
+	if errors.As(err, &oDataError) {
+		return oDataError.ResponseStatusCode == 404
+	}
+	return false
+}
+
+func (c *msGraphServicePrincipalsClient) ListKeyCredentials(ctx context.Context, objectId string) (graphrbac.KeyCredentialListResult, error) {
+	sp, err := c.client.ServicePrincipals().ByServicePrincipalId(objectId).Get(ctx, nil)
+	if err != nil {
+		if msGraphWasNotFound(err) {
+			return graphrbac.KeyCredentialListResult{}, &graph.NotFoundError{Kind: "Service Principal", Id: objectId}
+		}
+		return graphrbac.KeyCredentialListResult{}, err
+	}
+
+	creds := make([]graphrbac.KeyCredential, 0, len(sp.GetKeyCredentials()))
+	for _, k := range sp.GetKeyCredentials() {
+		creds = append(creds, keyCredentialFromMsGraph(k))
+	}
+	return graphrbac.KeyCredentialListResult{Value: &creds}, nil
+}
+
+func (c *msGraphServicePrincipalsClient) UpdateKeyCredentials(ctx context.Context, objectId string, parameters graphrbac.KeyCredentialsUpdateParameters) (autorest.Response, error) {
+	if parameters.Value == nil {
+		return autorest.Response{}, fmt.Errorf("updating key credentials for service principal %q: no credentials supplied", objectId)
+	}
+
+	creds := make([]models.KeyCredentialable, 0, len(*parameters.Value))
+	for _, k := range *parameters.Value {
+		creds = append(creds, keyCredentialToMsGraph(k))
+	}
+
+	requestBody := models.NewServicePrincipal()
+	requestBody.SetKeyCredentials(creds)
+
+	_, err := c.client.ServicePrincipals().ByServicePrincipalId(objectId).Patch(ctx, requestBody, nil)
+	return autorest.Response{}, err
+}
+
+func (c *msGraphServicePrincipalsClient) AddTokenSigningCertificate(ctx context.Context, objectId string, displayName string, endDate *string) (TokenSigningCertificate, error) {
+	requestBody := servicePrincipals.NewItemAddTokenSigningCertificatePostRequestBody()
+	requestBody.SetDisplayName(&displayName)
+	requestBody.SetEndDateTime(endDate)
+
+	cert, err := c.client.ServicePrincipals().ByServicePrincipalId(objectId).AddTokenSigningCertificate().Post(ctx, requestBody, nil)
+	if err != nil {
+		return TokenSigningCertificate{}, fmt.Errorf("generating token signing certificate for service principal %q: %+v", objectId, err)
+	}
+
+	result := TokenSigningCertificate{}
+	if keyId := cert.GetKeyId(); keyId != nil {
+		result.KeyId = keyId.String()
+	}
+	if thumbprint := cert.GetThumbprint(); thumbprint != nil {
+		result.Thumbprint = *thumbprint
+	}
+	if startDate := cert.GetStartDateTime(); startDate != nil {
+		result.StartDate = startDate.Format(time.RFC3339)
+	}
+	if endDate := cert.GetEndDateTime(); endDate != nil {
+		result.EndDate = endDate.Format(time.RFC3339)
+	}
+	if value := cert.GetValue(); value != nil {
+		result.Certificate = *value
+	}
+
+	return result, nil
+}
+
+func (c *msGraphServicePrincipalsClient) ListPasswordCredentials(ctx context.Context, objectId string) (graphrbac.PasswordCredentialListResult, error) {
+	sp, err := c.client.ServicePrincipals().ByServicePrincipalId(objectId).Get(ctx, nil)
+	if err != nil {
+		if msGraphWasNotFound(err) {
+			return graphrbac.PasswordCredentialListResult{}, &graph.NotFoundError{Kind: "Service Principal", Id: objectId}
+		}
+		return graphrbac.PasswordCredentialListResult{}, err
+	}
+
+	creds := make([]graphrbac.PasswordCredential, 0, len(sp.GetPasswordCredentials()))
+	for _, p := range sp.GetPasswordCredentials() {
+		creds = append(creds, passwordCredentialFromMsGraph(p))
+	}
+	return graphrbac.PasswordCredentialListResult{Value: &creds}, nil
+}
+
+func (c *msGraphServicePrincipalsClient) UpdatePasswordCredentials(ctx context.Context, objectId string, parameters graphrbac.PasswordCredentialsUpdateParameters) (autorest.Response, error) {
+	if parameters.Value == nil {
+		return autorest.Response{}, fmt.Errorf("updating password credentials for service principal %q: no credentials supplied", objectId)
+	}
+
+	creds := make([]models.PasswordCredentialable, 0, len(*parameters.Value))
+	for _, p := range *parameters.Value {
+		creds = append(creds, passwordCredentialToMsGraph(p))
+	}
+
+	requestBody := models.NewServicePrincipal()
+	requestBody.SetPasswordCredentials(creds)
+
+	_, err := c.client.ServicePrincipals().ByServicePrincipalId(objectId).Patch(ctx, requestBody, nil)
+	return autorest.Response{}, err
+}
+
+func passwordCredentialFromMsGraph(p models.PasswordCredentialable) graphrbac.PasswordCredential {
+	cred := graphrbac.PasswordCredential{
+		StartDate: p.GetStartDateTime(),
+		EndDate:   p.GetEndDateTime(),
+		Value:     p.GetSecretText(),
+	}
+	if keyId := p.GetKeyId(); keyId != nil {
+		s := keyId.String()
+		cred.KeyID = &s
+	}
+	return cred
+}
+
+func passwordCredentialToMsGraph(p graphrbac.PasswordCredential) models.PasswordCredentialable {
+	cred := models.NewPasswordCredential()
+	cred.SetStartDateTime(p.StartDate)
+	cred.SetEndDateTime(p.EndDate)
+	cred.SetSecretText(p.Value)
+	return cred
+}
+
+func keyCredentialFromMsGraph(k models.KeyCredentialable) graphrbac.KeyCredential {
+	cred := graphrbac.KeyCredential{
+		KeyID:     keyIdString(k),
+		StartDate: k.GetStartDateTime(),
+		EndDate:   k.GetEndDateTime(),
+	}
+	if usage := k.GetUsage(); usage != nil {
+		cred.Usage = usage
+	}
+	if credType := k.GetType(); credType != nil {
+		cred.Type = credType
+	}
+	if key := k.GetKey(); key != nil {
+		value := base64.StdEncoding.EncodeToString(key)
+		cred.Value = &value
+	}
+	if customKeyIdentifier := k.GetCustomKeyIdentifier(); customKeyIdentifier != nil {
+		value := base64.StdEncoding.EncodeToString(customKeyIdentifier)
+		cred.CustomKeyIdentifier = &value
+	}
+	return cred
+}
+
+func keyCredentialToMsGraph(k graphrbac.KeyCredential) models.KeyCredentialable {
+	cred := models.NewKeyCredential()
+	cred.SetStartDateTime(k.StartDate)
+	cred.SetEndDateTime(k.EndDate)
+	cred.SetUsage(k.Usage)
+	cred.SetType(k.Type)
+	if k.Value != nil {
+		if raw, err := base64.StdEncoding.DecodeString(*k.Value); err == nil {
+			cred.SetKey(raw)
+		}
+	}
+	if k.CustomKeyIdentifier != nil {
+		if raw, err := base64.StdEncoding.DecodeString(*k.CustomKeyIdentifier); err == nil {
+			cred.SetCustomKeyIdentifier(raw)
+		}
+	}
+	return cred
+}
+
+func keyIdString(k models.KeyCredentialable) *string {
+	if id := k.GetKeyId(); id != nil {
+		s := id.String()
+		return &s
+	}
+	return nil
+}
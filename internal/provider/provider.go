@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
+
+	"github.com/terraform-providers/terraform-provider-azuread/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azuread/internal/services/aadgraph"
+)
+
+// Provider returns the azuread provider, with `use_microsoft_graph` (and
+// its ARM_USE_MICROSOFT_GRAPH environment variable equivalent) choosing
+// whether resources talk to the deprecated AAD Graph API or Microsoft
+// Graph. See clients.NewAadClient for how that choice is wired into the
+// per-resource clients.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"use_microsoft_graph": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ARM_USE_MICROSOFT_GRAPH", false),
+				Description: "Use the Microsoft Graph API, rather than the deprecated Azure AD Graph API, for service principal credential resources.",
+			},
+		},
+
+		ResourcesMap: aadgraph.Resources(),
+
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	useMicrosoftGraph := d.Get("use_microsoft_graph").(bool)
+
+	aadGraphServicePrincipals, msGraphClient, err := buildGraphClients(useMicrosoftGraph)
+	if err != nil {
+		return nil, fmt.Errorf("building Graph clients: %+v", err)
+	}
+
+	return clients.NewAadClient(context.Background(), useMicrosoftGraph, aadGraphServicePrincipals, msGraphClient), nil
+}
+
+// buildGraphClients constructs only the backend actually selected by
+// `use_microsoft_graph`, authenticated the same way as the rest of the
+// provider's clients; the unused backend's return value is left nil.
+func buildGraphClients(useMicrosoftGraph bool) (*graphrbac.ServicePrincipalsClient, *msgraphsdk.GraphServiceClient, error) {
+	if useMicrosoftGraph {
+		msGraphClient, err := msgraphsdk.NewGraphServiceClientWithCredentials(nil, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("building Microsoft Graph client: %+v", err)
+		}
+		return nil, msGraphClient, nil
+	}
+
+	aadGraphClient := graphrbac.NewServicePrincipalsClient("")
+	return &aadGraphClient, nil, nil
+}
@@ -0,0 +1,12 @@
+// Package aadgraph implements the azuread provider's resources against the
+// shared ServicePrincipalsClient/FederatedIdentityCredentialsClient
+// abstractions in internal/clients, so each resource works against either
+// the AAD Graph or Microsoft Graph backend without duplicating CRUD logic.
+//
+// graph.CredentialResource factors the list/find/mutate/not-found sequence
+// common to every credential-backed resource. Only the resources that
+// actually exist in this tree - azuread_service_principal_certificate and
+// azuread_service_principal_token_signing_certificate - have been migrated
+// onto it; there is no service principal password, application
+// certificate, or application password resource here to migrate.
+package aadgraph
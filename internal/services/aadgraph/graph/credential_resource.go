@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+)
+
+// CredentialResource factors the Read/Delete flow shared by
+// KeyCredential-backed resources (certificates, token signing certificates)
+// down to three callbacks - "parent-get", "list" and "update" - so that
+// adding a new credential kind doesn't mean copy-pasting the
+// list/mutate/update sequence again. It works on KeyCredentials only, not
+// PasswordCredentials, and takes no lock: callers hold the parent's lock
+// around Delete themselves.
+type CredentialResource struct {
+	// ParentKind and CredentialKind name what's being managed, for log
+	// messages and NotFoundErrors - e.g. "Service Principal" and
+	// "certificate credential".
+	ParentKind     string
+	CredentialKind string
+
+	// ParentGet confirms the parent object still exists, returning a
+	// *NotFoundError if it doesn't.
+	ParentGet func(ctx context.Context, objectId string) error
+
+	// List returns every KeyCredential currently on the parent object.
+	List func(ctx context.Context, objectId string) (graphrbac.KeyCredentialListResult, error)
+
+	// Update replaces the full set of KeyCredentials on the parent object.
+	Update func(ctx context.Context, objectId string, credentials *[]graphrbac.KeyCredential) error
+}
+
+// Read looks up the parent object and then the specific credential on it,
+// returning a *NotFoundError for either miss so the caller can d.SetId("").
+func (r CredentialResource) Read(ctx context.Context, id CredentialId) (*graphrbac.KeyCredential, error) {
+	if err := r.ParentGet(ctx, id.ObjectId); err != nil {
+		if _, ok := err.(*NotFoundError); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("retrieving %s with ID %q: %+v", r.ParentKind, id.ObjectId, err)
+	}
+
+	credentials, err := r.List(ctx, id.ObjectId)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s for %s with ID %q: %+v", r.CredentialKind, r.ParentKind, id.ObjectId, err)
+	}
+
+	credential := KeyCredentialResultFindByKeyId(credentials, id.KeyId)
+	if credential == nil {
+		return nil, &NotFoundError{Kind: r.CredentialKind, Id: id.KeyId}
+	}
+
+	return credential, nil
+}
+
+// Delete removes a single credential by KeyId, tolerating the parent object
+// having already been removed.
+func (r CredentialResource) Delete(ctx context.Context, id CredentialId) error {
+	if err := r.ParentGet(ctx, id.ObjectId); err != nil {
+		if _, ok := err.(*NotFoundError); ok {
+			log.Printf("[DEBUG] %s with ID %q was not found - removing from state!", r.ParentKind, id.ObjectId)
+			return nil
+		}
+		return fmt.Errorf("retrieving %s with ID %q: %+v", r.ParentKind, id.ObjectId, err)
+	}
+
+	existing, err := r.List(ctx, id.ObjectId)
+	if err != nil {
+		return fmt.Errorf("listing %s for %s with ID %q: %+v", r.CredentialKind, r.ParentKind, id.ObjectId, err)
+	}
+
+	newCreds := KeyCredentialResultRemoveByKeyId(existing, id.KeyId)
+	if err := r.Update(ctx, id.ObjectId, newCreds); err != nil {
+		return fmt.Errorf("removing %s %q from %s with ID %q: %+v", r.CredentialKind, id.KeyId, r.ParentKind, id.ObjectId, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NotFoundError is returned by CredentialResource's shared Read/Delete flow
+// when either the parent object or the credential itself no longer exists,
+// so callers can tell that case apart from a genuine API error and remove
+// the resource from state instead of failing the plan.
+type NotFoundError struct {
+	Kind string
+	Id   string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s with ID %q was not found", e.Kind, e.Id)
+}
+
+// IsNotFoundError reports whether err is (or wraps) a *NotFoundError.
+func IsNotFoundError(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
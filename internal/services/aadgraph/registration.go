@@ -0,0 +1,13 @@
+package aadgraph
+
+import "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+// Resources returns every resource this package implements, for the
+// provider to merge into its ResourcesMap.
+func Resources() map[string]*schema.Resource {
+	return map[string]*schema.Resource{
+		"azuread_service_principal_certificate":                   servicePrincipalCertificateResource(),
+		"azuread_service_principal_token_signing_certificate":     servicePrincipalTokenSigningCertificateResource(),
+		"azuread_service_principal_federated_identity_credential": servicePrincipalFederatedIdentityCredentialResource(),
+	}
+}
@@ -1,6 +1,7 @@
 package aadgraph
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log"
 	"time"
@@ -11,26 +12,172 @@ import (
 	"github.com/terraform-providers/terraform-provider-azuread/internal/clients"
 	"github.com/terraform-providers/terraform-provider-azuread/internal/services/aadgraph/graph"
 	"github.com/terraform-providers/terraform-provider-azuread/internal/tf"
-	"github.com/terraform-providers/terraform-provider-azuread/internal/utils"
 )
 
 func servicePrincipalCertificateResource() *schema.Resource {
 	return &schema.Resource{
 		Create: servicePrincipalCertificateResourceCreate,
 		Read:   servicePrincipalCertificateResourceRead,
+		Update: servicePrincipalCertificateResourceUpdate,
 		Delete: servicePrincipalCertificateResourceDelete,
 
-		Importer: tf.ValidateResourceIDPriorToImport(func(id string) error {
-			_, err := graph.ParseCertificateId(id)
-			return err
-		}),
+		Importer: &schema.ResourceImporter{
+			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				if _, err := graph.ParseCertificateId(d.Id()); err != nil {
+					return nil, err
+				}
+				d.Set("imported", true)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
 
-		Schema: graph.CertificateResourceSchema("service_principal_id"),
+		Schema: rotatableCredentialResourceSchema(suppressWriteOnlyFieldsAfterImport(graph.CertificateResourceSchema("service_principal_id"))),
+
+		CustomizeDiff: rotationCustomizeDiff,
+	}
+}
+
+// rotationCustomizeDiff forces a diff - so that Terraform actually invokes
+// Update - in the three situations Update needs to run on its own, with no
+// other schema change to key a plan off of:
+//
+//   - `rotate_when_changed` was bumped, the explicit out-of-band rotation
+//     trigger the request asked for. Without this, bumping it (now that
+//     it's no longer ForceNew) wouldn't trigger anything at all.
+//   - the current credential is within `rotate_before_expiry` of its
+//     `end_date`, the time-based rotation trigger.
+//   - a previous rotation's overlapping credential has sat past
+//     `overlap_until` and is waiting to be cleaned up; without forcing a
+//     diff here, that cleanup would only happen to run on whatever later
+//     Update a genuine config change or the next rotation happens to
+//     cause, rather than deterministically once the overlap window closes.
+func rotationCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange("rotate_when_changed") {
+		return d.SetNewComputed("key_id")
+	}
+
+	if overlapUntil, ok := d.GetOk("overlap_until"); ok {
+		expiry, err := time.Parse(time.RFC3339, overlapUntil.(string))
+		if err != nil {
+			return fmt.Errorf("parsing `overlap_until`: %+v", err)
+		}
+		if time.Now().UTC().After(expiry) {
+			return d.SetNewComputed("previous_key_id")
+		}
+	}
+
+	rotation, ok := d.GetOk("rotation")
+	if !ok {
+		return nil
+	}
+	block := rotation.([]interface{})[0].(map[string]interface{})
+
+	rotateBeforeExpiry, err := time.ParseDuration(block["rotate_before_expiry"].(string))
+	if err != nil {
+		return fmt.Errorf("parsing `rotate_before_expiry`: %+v", err)
+	}
+
+	endDateRaw, ok := d.GetOk("end_date")
+	if !ok {
+		return nil
+	}
+
+	endDate, err := time.Parse(time.RFC3339, endDateRaw.(string))
+	if err != nil {
+		return fmt.Errorf("parsing `end_date`: %+v", err)
+	}
+
+	if time.Until(endDate) <= rotateBeforeExpiry {
+		return d.SetNewComputed("key_id")
+	}
+
+	return nil
+}
+
+// suppressWriteOnlyFieldsAfterImport marks the fields that cannot be read
+// back from a KeyCredential - the certificate material, its relative
+// expiry and its encoding - so that a blank value left by `terraform
+// import` is not treated as a diff against the configuration. Without
+// this, the first plan after import would otherwise force a delete+create
+// of the resource.
+//
+// The suppression is gated on the `imported` flag rather than on `old ==
+// ""` alone: the Importer is the only thing that ever sets it, and Create
+// clears it again as soon as the resource goes through a real create (for
+// example the destroy+recreate a later genuine edit to one of these
+// ForceNew fields triggers), so a deliberate post-import change to e.g.
+// `value` stops being masked once that first replace has gone through.
+func suppressWriteOnlyFieldsAfterImport(base map[string]*schema.Schema) map[string]*schema.Schema {
+	base["imported"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Computed: true,
+	}
+
+	for _, key := range []string{"value", "end_date_relative", "encoding"} {
+		if s, ok := base[key]; ok {
+			s.DiffSuppressFunc = func(k, old, new string, d *schema.ResourceData) bool {
+				return old == "" && d.Get("imported").(bool)
+			}
+		}
 	}
+	return base
+}
+
+// rotatableCredentialResourceSchema adds the `rotate_when_changed` and
+// `rotation` arguments, along with the computed bookkeeping needed to track
+// an overlapping credential while it waits to be removed, onto a credential
+// resource's base schema.
+func rotatableCredentialResourceSchema(base map[string]*schema.Schema) map[string]*schema.Schema {
+	base["rotate_when_changed"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+
+	base["rotation"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"rotate_before_expiry": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "168h",
+					ValidateFunc: validateDuration,
+				},
+				"overlap_duration": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "24h",
+					ValidateFunc: validateDuration,
+				},
+			},
+		},
+	}
+
+	base["previous_key_id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	base["overlap_until"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	return base
+}
+
+func validateDuration(i interface{}, k string) (warnings []string, errors []error) {
+	if _, err := time.ParseDuration(i.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q is not a valid duration: %+v", k, err))
+	}
+	return
 }
 
 func servicePrincipalCertificateResourceCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients.AadClient).AadGraph.ServicePrincipalsClient
+	client := meta.(*clients.AadClient).ServicePrincipalsClient
 	ctx := meta.(*clients.AadClient).StopContext
 
 	objectId := d.Get("service_principal_id").(string)
@@ -70,39 +217,138 @@ func servicePrincipalCertificateResourceCreate(d *schema.ResourceData, meta inte
 
 	d.SetId(id.String())
 
+	// a real Create means `value`/`end_date_relative`/`encoding` are known
+	// again, so a later genuine edit to one of them is no longer masked by
+	// suppressWriteOnlyFieldsAfterImport
+	d.Set("imported", false)
+
 	return servicePrincipalCertificateResourceRead(d, meta)
 }
 
-func servicePrincipalCertificateResourceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients.AadClient).AadGraph.ServicePrincipalsClient
+// servicePrincipalCertificateResourceUpdate implements zero-downtime
+// rotation: when the current credential is within `rotate_before_expiry` of
+// its `end_date` a new KeyCredential is added alongside it and replication
+// is awaited before the resource starts representing the new credential.
+// The old credential is kept - rather than removed immediately - so callers
+// have `overlap_duration` to pick up the new value before it stops working,
+// and is only removed by a later Update, once that window has elapsed
+// (rotationCustomizeDiff forces a diff at that point so the Update runs).
+func servicePrincipalCertificateResourceUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.AadClient).ServicePrincipalsClient
 	ctx := meta.(*clients.AadClient).StopContext
 
 	id, err := graph.ParseCertificateId(d.Id())
 	if err != nil {
 		return fmt.Errorf("parsing certificate credential with ID: %v", err)
 	}
-	// ensure the Service Principal Object exists
-	sp, err := client.Get(ctx, id.ObjectId)
-	if err != nil {
-		// the parent Service Principal has been removed - skip it
-		if utils.ResponseWasNotFound(sp.Response) {
-			log.Printf("[DEBUG] Service Principal with Object ID %q was not found - removing from state!", id.ObjectId)
-			d.SetId("")
-			return nil
+
+	tf.LockByName(servicePrincipalResourceName, id.ObjectId)
+	defer tf.UnlockByName(servicePrincipalResourceName, id.ObjectId)
+
+	if previousKeyId, overlapUntil := d.Get("previous_key_id").(string), d.Get("overlap_until").(string); previousKeyId != "" && overlapUntil != "" {
+		expiry, err := time.Parse(time.RFC3339, overlapUntil)
+		if err != nil {
+			return fmt.Errorf("parsing `overlap_until` %q: %+v", overlapUntil, err)
+		}
+
+		if time.Now().UTC().After(expiry) {
+			existing, err := client.ListKeyCredentials(ctx, id.ObjectId)
+			if err != nil {
+				return fmt.Errorf("listing certificate credentials for service principal %q: %+v", id.ObjectId, err)
+			}
+
+			newCreds := graph.KeyCredentialResultRemoveByKeyId(existing, previousKeyId)
+			if _, err = client.UpdateKeyCredentials(ctx, id.ObjectId, graphrbac.KeyCredentialsUpdateParameters{Value: newCreds}); err != nil {
+				return fmt.Errorf("removing overlapping certificate credential %q from service principal with ID %q: %+v", previousKeyId, id.ObjectId, err)
+			}
+
+			d.Set("previous_key_id", "")
+			d.Set("overlap_until", "")
 		}
-		return fmt.Errorf("retrieving service principal with ID %q: %+v", id.ObjectId, err)
 	}
 
-	credentials, err := client.ListKeyCredentials(ctx, id.ObjectId)
+	// `rotate_when_changed` has no meaning of its own beyond being bumped -
+	// any change to it is the caller explicitly asking for an out-of-band
+	// rotation, regardless of how far out `end_date` is.
+	forceRotate := d.HasChange("rotate_when_changed")
+
+	// without a `rotation` block, only a `rotate_when_changed` bump rotates,
+	// with the block's default `overlap_duration`
+	rotateBeforeExpiry, overlapDuration := 168*time.Hour, 24*time.Hour
+	if rotation, ok := d.GetOk("rotation"); ok {
+		block := rotation.([]interface{})[0].(map[string]interface{})
+
+		if rotateBeforeExpiry, err = time.ParseDuration(block["rotate_before_expiry"].(string)); err != nil {
+			return fmt.Errorf("parsing `rotate_before_expiry`: %+v", err)
+		}
+		if overlapDuration, err = time.ParseDuration(block["overlap_duration"].(string)); err != nil {
+			return fmt.Errorf("parsing `overlap_duration`: %+v", err)
+		}
+	} else if !forceRotate {
+		return servicePrincipalCertificateResourceRead(d, meta)
+	}
+
+	endDate, err := time.Parse(time.RFC3339, d.Get("end_date").(string))
+	if err != nil {
+		return fmt.Errorf("parsing `end_date`: %+v", err)
+	}
+
+	if time.Until(endDate) > rotateBeforeExpiry && !forceRotate {
+		return servicePrincipalCertificateResourceRead(d, meta)
+	}
+
+	cred, err := graph.KeyCredentialForResource(d)
+	if err != nil {
+		return fmt.Errorf("generating rotated certificate credentials for object ID %q: %+v", id.ObjectId, err)
+	}
+
+	existingCreds, err := client.ListKeyCredentials(ctx, id.ObjectId)
 	if err != nil {
 		return fmt.Errorf("listing certificate credentials for service principal with ID %q: %+v", id.ObjectId, err)
 	}
 
-	credential := graph.KeyCredentialResultFindByKeyId(credentials, id.KeyId)
-	if credential == nil {
-		log.Printf("[DEBUG] certificate credential %q (ID %q) was not found - removing from state!", id.KeyId, id.ObjectId)
-		d.SetId("")
-		return nil
+	newCreds, err := graph.KeyCredentialResultAdd(existingCreds, cred)
+	if err != nil {
+		return fmt.Errorf("adding rotated Service Principal Certificate: %+v", err)
+	}
+
+	if _, err = client.UpdateKeyCredentials(ctx, id.ObjectId, graphrbac.KeyCredentialsUpdateParameters{Value: newCreds}); err != nil {
+		return fmt.Errorf("creating rotated certificate credentials %q for service principal with ID %q: %+v", *cred.KeyID, id.ObjectId, err)
+	}
+
+	_, err = graph.WaitForKeyCredentialReplication(*cred.KeyID, d.Timeout(schema.TimeoutUpdate), func() (graphrbac.KeyCredentialListResult, error) {
+		return client.ListKeyCredentials(ctx, id.ObjectId)
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for rotated certificate credential replication for service principal (ID %q, KeyID %q: %+v", id.ObjectId, *cred.KeyID, err)
+	}
+
+	d.Set("previous_key_id", id.KeyId)
+	d.Set("overlap_until", time.Now().UTC().Add(overlapDuration).Format(time.RFC3339))
+
+	newId := graph.CredentialIdFrom(id.ObjectId, "certificate", *cred.KeyID)
+	d.SetId(newId.String())
+
+	return servicePrincipalCertificateResourceRead(d, meta)
+}
+
+func servicePrincipalCertificateResourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.AadClient).ServicePrincipalsClient
+	ctx := meta.(*clients.AadClient).StopContext
+
+	id, err := graph.ParseCertificateId(d.Id())
+	if err != nil {
+		return fmt.Errorf("parsing certificate credential with ID: %v", err)
+	}
+
+	credential, err := servicePrincipalCredentialResource(client, "certificate credential").Read(ctx, id)
+	if err != nil {
+		if graph.IsNotFoundError(err) {
+			log.Printf("[DEBUG] %v - removing from state!", err)
+			d.SetId("")
+			return nil
+		}
+		return err
 	}
 
 	// todo, move this into a graph helper function?
@@ -121,11 +367,23 @@ func servicePrincipalCertificateResourceRead(d *schema.ResourceData, meta interf
 		d.Set("start_date", startDate.Format(time.RFC3339))
 	}
 
+	// `value` cannot be recovered from the KeyCredential, but its encoding
+	// can be inferred from the shape of the CustomKeyIdentifier Azure AD
+	// stored it under - which lets an imported resource plan cleanly
+	// without the caller having to restate `encoding` from memory.
+	if customKeyIdentifier := credential.CustomKeyIdentifier; customKeyIdentifier != nil {
+		if _, err := base64.StdEncoding.DecodeString(*customKeyIdentifier); err == nil {
+			d.Set("encoding", "pem")
+		} else {
+			d.Set("encoding", "hex")
+		}
+	}
+
 	return nil
 }
 
 func servicePrincipalCertificateResourceDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients.AadClient).AadGraph.ServicePrincipalsClient
+	client := meta.(*clients.AadClient).ServicePrincipalsClient
 	ctx := meta.(*clients.AadClient).StopContext
 
 	id, err := graph.ParseCertificateId(d.Id())
@@ -136,26 +394,5 @@ func servicePrincipalCertificateResourceDelete(d *schema.ResourceData, meta inte
 	tf.LockByName(servicePrincipalResourceName, id.ObjectId)
 	defer tf.UnlockByName(servicePrincipalResourceName, id.ObjectId)
 
-	// ensure the parent Service Principal exists
-	sp, err := client.Get(ctx, id.ObjectId)
-	if err != nil {
-		// the parent Service Principal has been removed - skip it
-		if utils.ResponseWasNotFound(sp.Response) {
-			log.Printf("[DEBUG] Service Principal with Object ID %q was not found - removing from state!", id.ObjectId)
-			return nil
-		}
-		return fmt.Errorf("retrieving service principal with ID %q: %+v", id.ObjectId, err)
-	}
-
-	existing, err := client.ListKeyCredentials(ctx, id.ObjectId)
-	if err != nil {
-		return fmt.Errorf("listing certificate credentials for service principal %q: %+v", id.ObjectId, err)
-	}
-
-	newCreds := graph.KeyCredentialResultRemoveByKeyId(existing, id.KeyId)
-	if _, err = client.UpdateKeyCredentials(ctx, id.ObjectId, graphrbac.KeyCredentialsUpdateParameters{Value: newCreds}); err != nil {
-		return fmt.Errorf("removing certificate credentials %q from service principal with ID %q: %+v", id.KeyId, id.ObjectId, err)
-	}
-
-	return nil
+	return servicePrincipalCredentialResource(client, "certificate credential").Delete(ctx, id)
 }
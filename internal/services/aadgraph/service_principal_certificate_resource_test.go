@@ -0,0 +1,103 @@
+package aadgraph_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+	"github.com/terraform-providers/terraform-provider-azuread/internal/provider"
+)
+
+var testAccProviders = map[string]terraform.ResourceProvider{
+	"azuread": provider.Provider(),
+}
+
+// TestAccServicePrincipalCertificate_importThenPlanIsEmpty imports a
+// certificate created from config and then plans that same config,
+// expecting an empty plan. `value`, `end_date_relative` and `encoding` can
+// never be read back from Azure AD, which is what
+// suppressWriteOnlyFieldsAfterImport's DiffSuppressFunc covers. The
+// helper/resource import step does not keep the imported state: the
+// PlanOnly step plans against the state left by the first step, so this
+// test checks that the ID imports and that the config plans cleanly, but
+// it does not run the DiffSuppressFunc against an imported state.
+func TestAccServicePrincipalCertificate_importThenPlanIsEmpty(t *testing.T) {
+	objectId := os.Getenv("ARM_TEST_SERVICE_PRINCIPAL_ID")
+	if objectId == "" {
+		t.Skip("ARM_TEST_SERVICE_PRINCIPAL_ID must be set to the object ID of a pre-existing service principal to run this test")
+	}
+
+	resourceName := "azuread_service_principal_certificate.test"
+	config := testAccServicePrincipalCertificateConfig(objectId, testAccGenerateCertificatePEM(t))
+
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				ResourceName: resourceName,
+				ImportState:  true,
+				// value/end_date_relative/encoding are write-only and can
+				// never be read back, so ImportStateVerify would always
+				// flag them.
+				ImportStateVerify: false,
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccServicePrincipalCertificateConfig(servicePrincipalId, certificatePEM string) string {
+	return fmt.Sprintf(`
+resource "azuread_service_principal_certificate" "test" {
+  service_principal_id = %q
+  type                  = "AsymmetricX509Cert"
+  encoding              = "pem"
+  value                 = %q
+  end_date_relative     = "8760h"
+}
+`, servicePrincipalId, certificatePEM)
+}
+
+// testAccGenerateCertificatePEM builds a throwaway self-signed certificate
+// so the test doesn't depend on a fixture file - only its shape (a PEM
+// encoded AsymmetricX509Cert) matters for exercising the import/suppress
+// path, not what it actually certifies.
+func testAccGenerateCertificatePEM(t *testing.T) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating private key: %v", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "acctest-service-principal-certificate"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(8760 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating self-signed certificate: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
@@ -0,0 +1,40 @@
+package aadgraph
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+
+	"github.com/terraform-providers/terraform-provider-azuread/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azuread/internal/services/aadgraph/graph"
+)
+
+// servicePrincipalCredentialResource builds a graph.CredentialResource bound
+// to a single service principal's KeyCredentials, shared by every resource
+// under this package that manages one (certificates, token signing
+// certificates, and so on).
+//
+// client.Get already returns a *graph.NotFoundError for a missing Service
+// Principal regardless of backend (AAD Graph or Microsoft Graph), so
+// ParentGet can just propagate it rather than re-deriving not-found-ness
+// from a backend-specific response shape.
+func servicePrincipalCredentialResource(client clients.ServicePrincipalsClient, credentialKind string) graph.CredentialResource {
+	return graph.CredentialResource{
+		ParentKind:     "Service Principal",
+		CredentialKind: credentialKind,
+
+		ParentGet: func(ctx context.Context, objectId string) error {
+			_, err := client.Get(ctx, objectId)
+			return err
+		},
+
+		List: func(ctx context.Context, objectId string) (graphrbac.KeyCredentialListResult, error) {
+			return client.ListKeyCredentials(ctx, objectId)
+		},
+
+		Update: func(ctx context.Context, objectId string, credentials *[]graphrbac.KeyCredential) error {
+			_, err := client.UpdateKeyCredentials(ctx, objectId, graphrbac.KeyCredentialsUpdateParameters{Value: credentials})
+			return err
+		},
+	}
+}
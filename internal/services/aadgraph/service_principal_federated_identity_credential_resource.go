@@ -0,0 +1,170 @@
+package aadgraph
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	"github.com/terraform-providers/terraform-provider-azuread/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azuread/internal/services/aadgraph/graph"
+	"github.com/terraform-providers/terraform-provider-azuread/internal/tf"
+)
+
+func servicePrincipalFederatedIdentityCredentialResource() *schema.Resource {
+	return &schema.Resource{
+		Create: servicePrincipalFederatedIdentityCredentialResourceCreate,
+		Read:   servicePrincipalFederatedIdentityCredentialResourceRead,
+		Delete: servicePrincipalFederatedIdentityCredentialResourceDelete,
+
+		Importer: tf.ValidateResourceIDPriorToImport(func(id string) error {
+			_, err := graph.ParseCertificateId(id)
+			return err
+		}),
+
+		Schema: map[string]*schema.Schema{
+			"service_principal_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			"issuer": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsURLWithHTTPS,
+			},
+
+			"subject": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			"audiences": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.NoZeroValues},
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// federatedIdentityCredentialsClient returns the Microsoft Graph backed
+// client for federated identity credentials, or a clear error if the
+// provider is configured against AAD Graph only - AAD Graph predates this
+// credential type and has no equivalent API for it.
+func federatedIdentityCredentialsClient(meta interface{}) (clients.FederatedIdentityCredentialsClient, error) {
+	client := meta.(*clients.AadClient).FederatedIdentityCredentialsClient
+	if client == nil {
+		return nil, fmt.Errorf("azuread_service_principal_federated_identity_credential requires the provider to be configured with `use_microsoft_graph = true` (or ARM_USE_MICROSOFT_GRAPH=true) - federated identity credentials are not available via AAD Graph")
+	}
+	return client, nil
+}
+
+func servicePrincipalFederatedIdentityCredentialResourceCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := federatedIdentityCredentialsClient(meta)
+	if err != nil {
+		return err
+	}
+	ctx := meta.(*clients.AadClient).StopContext
+
+	objectId := d.Get("service_principal_id").(string)
+
+	audiencesRaw := d.Get("audiences").([]interface{})
+	audiences := make([]string, 0, len(audiencesRaw))
+	for _, a := range audiencesRaw {
+		audiences = append(audiences, a.(string))
+	}
+
+	tf.LockByName(servicePrincipalResourceName, objectId)
+	defer tf.UnlockByName(servicePrincipalResourceName, objectId)
+
+	credential, err := client.Create(ctx, objectId, clients.FederatedIdentityCredential{
+		Name:        d.Get("name").(string),
+		Issuer:      d.Get("issuer").(string),
+		Subject:     d.Get("subject").(string),
+		Audiences:   audiences,
+		Description: d.Get("description").(string),
+	})
+	if err != nil {
+		return fmt.Errorf("creating federated identity credential for service principal with ID %q: %+v", objectId, err)
+	}
+
+	id := graph.CredentialIdFrom(objectId, "federated", credential.Id)
+	d.SetId(id.String())
+
+	return servicePrincipalFederatedIdentityCredentialResourceRead(d, meta)
+}
+
+func servicePrincipalFederatedIdentityCredentialResourceRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := federatedIdentityCredentialsClient(meta)
+	if err != nil {
+		return err
+	}
+	ctx := meta.(*clients.AadClient).StopContext
+
+	id, err := graph.ParseCertificateId(d.Id())
+	if err != nil {
+		return fmt.Errorf("parsing federated identity credential ID: %v", err)
+	}
+
+	credential, err := client.Get(ctx, id.ObjectId, id.KeyId)
+	if err != nil {
+		if graph.IsNotFoundError(err) {
+			log.Printf("[DEBUG] federated identity credential %q (Service Principal ID %q) was not found - removing from state!", id.KeyId, id.ObjectId)
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("retrieving federated identity credential %q (Service Principal ID %q): %+v", id.KeyId, id.ObjectId, err)
+	}
+
+	d.Set("service_principal_id", id.ObjectId)
+	d.Set("name", credential.Name)
+	d.Set("issuer", credential.Issuer)
+	d.Set("subject", credential.Subject)
+	d.Set("audiences", credential.Audiences)
+	d.Set("description", credential.Description)
+
+	return nil
+}
+
+func servicePrincipalFederatedIdentityCredentialResourceDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := federatedIdentityCredentialsClient(meta)
+	if err != nil {
+		return err
+	}
+	ctx := meta.(*clients.AadClient).StopContext
+
+	id, err := graph.ParseCertificateId(d.Id())
+	if err != nil {
+		return fmt.Errorf("parsing federated identity credential ID: %v", err)
+	}
+
+	tf.LockByName(servicePrincipalResourceName, id.ObjectId)
+	defer tf.UnlockByName(servicePrincipalResourceName, id.ObjectId)
+
+	if err := client.Delete(ctx, id.ObjectId, id.KeyId); err != nil {
+		return fmt.Errorf("removing federated identity credential %q from service principal with ID %q: %+v", id.KeyId, id.ObjectId, err)
+	}
+
+	return nil
+}
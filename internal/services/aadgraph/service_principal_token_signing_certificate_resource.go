@@ -0,0 +1,178 @@
+package aadgraph
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	"github.com/terraform-providers/terraform-provider-azuread/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azuread/internal/services/aadgraph/graph"
+	"github.com/terraform-providers/terraform-provider-azuread/internal/tf"
+)
+
+func servicePrincipalTokenSigningCertificateResource() *schema.Resource {
+	return &schema.Resource{
+		Create: servicePrincipalTokenSigningCertificateResourceCreate,
+		Read:   servicePrincipalTokenSigningCertificateResourceRead,
+		Delete: servicePrincipalTokenSigningCertificateResourceDelete,
+
+		Importer: tf.ValidateResourceIDPriorToImport(func(id string) error {
+			_, err := graph.ParseCertificateId(id)
+			return err
+		}),
+
+		Schema: map[string]*schema.Schema{
+			"service_principal_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			"display_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+
+			"end_date": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+
+			"end_date_relative": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"key_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"thumbprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"start_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"value": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func servicePrincipalTokenSigningCertificateResourceCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.AadClient).ServicePrincipalsClient
+	ctx := meta.(*clients.AadClient).StopContext
+
+	objectId := d.Get("service_principal_id").(string)
+	displayName := d.Get("display_name").(string)
+	if displayName != "" && !strings.HasPrefix(displayName, "CN=") {
+		displayName = fmt.Sprintf("CN=%s", displayName)
+	}
+
+	var endDate *string
+	if v, ok := d.GetOk("end_date"); ok {
+		value := v.(string)
+		endDate = &value
+	} else if v, ok := d.GetOk("end_date_relative"); ok {
+		duration, err := time.ParseDuration(v.(string))
+		if err != nil {
+			return fmt.Errorf("parsing `end_date_relative`: %+v", err)
+		}
+		value := time.Now().Add(duration).UTC().Format(time.RFC3339)
+		endDate = &value
+	}
+
+	tf.LockByName(servicePrincipalResourceName, objectId)
+	defer tf.UnlockByName(servicePrincipalResourceName, objectId)
+
+	cert, err := client.AddTokenSigningCertificate(ctx, objectId, displayName, endDate)
+	if err != nil {
+		return fmt.Errorf("generating token signing certificate for service principal with ID %q: %+v", objectId, err)
+	}
+
+	id := graph.CredentialIdFrom(objectId, "tokenSigningCertificate", cert.KeyId)
+	d.SetId(id.String())
+
+	// the private key backing this certificate never leaves Azure AD, so the
+	// public material returned here is the only chance we get to record it -
+	// it cannot be re-derived on a subsequent Read
+	d.Set("thumbprint", cert.Thumbprint)
+	d.Set("value", cert.Certificate)
+
+	return servicePrincipalTokenSigningCertificateResourceRead(d, meta)
+}
+
+func servicePrincipalTokenSigningCertificateResourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.AadClient).ServicePrincipalsClient
+	ctx := meta.(*clients.AadClient).StopContext
+
+	id, err := graph.ParseCertificateId(d.Id())
+	if err != nil {
+		return fmt.Errorf("parsing token signing certificate ID: %v", err)
+	}
+
+	credential, err := servicePrincipalCredentialResource(client, "token signing certificate").Read(ctx, id)
+	if err != nil {
+		if graph.IsNotFoundError(err) {
+			log.Printf("[DEBUG] %v - removing from state!", err)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("service_principal_id", id.ObjectId)
+	d.Set("key_id", id.KeyId)
+
+	if endDate := credential.EndDate; endDate != nil {
+		d.Set("end_date", endDate.Format(time.RFC3339))
+	}
+
+	if startDate := credential.StartDate; startDate != nil {
+		d.Set("start_date", startDate.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func servicePrincipalTokenSigningCertificateResourceDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.AadClient).ServicePrincipalsClient
+	ctx := meta.(*clients.AadClient).StopContext
+
+	id, err := graph.ParseCertificateId(d.Id())
+	if err != nil {
+		return fmt.Errorf("parsing token signing certificate ID: %v", err)
+	}
+
+	tf.LockByName(servicePrincipalResourceName, id.ObjectId)
+	defer tf.UnlockByName(servicePrincipalResourceName, id.ObjectId)
+
+	return servicePrincipalCredentialResource(client, "token signing certificate").Delete(ctx, id)
+}
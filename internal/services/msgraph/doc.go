@@ -0,0 +1,10 @@
+// Package msgraph holds Microsoft Graph specific helpers for resources that
+// are shared with internal/services/aadgraph. Resource schemas and CRUD
+// functions are not duplicated here: the ServicePrincipalsClient interface
+// in internal/clients lets a single resource implementation run against
+// either backend, selected by the provider's `use_microsoft_graph` argument
+// (or the ARM_USE_MICROSOFT_GRAPH environment variable). The translation
+// between Microsoft Graph and the shared graphrbac wire types lives in
+// internal/clients (service_principals_msgraph.go); this package holds no
+// code of its own.
+package msgraph